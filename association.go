@@ -0,0 +1,305 @@
+package gorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Association 关联关系操作句柄，通过(*DB).Association获得，
+// 封装了has_many/has_one/belongs_to/many_to_many四种关系的增删改查
+type Association struct {
+	scope  *Scope
+	column string
+	field  *Field
+	Error  error
+}
+
+// Association 返回column对应关联的操作句柄，column必须是一个已经建立好关联的字段名
+func (s *DB) Association(column string) *Association {
+	scope := s.clone().NewScope(s.Value)
+
+	field, ok := scope.FieldByName(column)
+	if !ok || field.Relationship == nil {
+		err := fmt.Errorf("%v doesn't have a relationship named %v", scope.typeName(), column)
+		scope.Err(err)
+		return &Association{Error: err}
+	}
+
+	return &Association{scope: scope, column: column, field: field}
+}
+
+// setError 记录错误，同时让DB.Error也能看到
+func (a *Association) setError(err error) *Association {
+	if err != nil {
+		a.Error = err
+		if a.scope != nil {
+			a.scope.Err(err)
+		}
+	}
+	return a
+}
+
+// setScopeError 把s执行SQL之后留在s.db.Error里的错误记下来，s.db为nil时什么都不做
+func (a *Association) setScopeError(s *Scope) *Association {
+	if s.db != nil {
+		a.setError(s.db.Error)
+	}
+	return a
+}
+
+// Find 把关联的记录查出来赋值给out
+func (a *Association) Find(out interface{}) *Association {
+	if a.Error != nil {
+		return a
+	}
+
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+
+	var db *DB
+	switch relationship.Kind {
+	case "has_many", "has_one":
+		dbName := ToSnake(relationship.ForeignKey)
+		db = a.scope.NewDB().Where(fmt.Sprintf("%v = ?", a.scope.Quote(dbName)), primaryKeyValue).Find(out)
+	case "belongs_to":
+		foreignKeyValue, _ := FieldValueByName(relationship.ForeignKey, a.scope.Value)
+		childScope := a.scope.New(out)
+		db = a.scope.NewDB().Table(childScope.TableName()).
+			Where(fmt.Sprintf("%v = ?", a.scope.Quote(childScope.PrimaryKey())), foreignKeyValue).Find(out)
+	case "many_to_many":
+		childScope := a.scope.New(out)
+		sourceKey := ToSnake(a.scope.typeName() + "Id")
+		associationKey := ToSnake(relationship.AssociationForeignKey)
+		destKey := childScope.PrimaryKey()
+
+		joinSql := fmt.Sprintf("INNER JOIN %v ON %v.%v = %v.%v",
+			a.scope.Quote(relationship.JoinTable), a.scope.Quote(relationship.JoinTable), associationKey,
+			a.scope.Quote(childScope.TableName()), destKey)
+
+		db = a.scope.NewDB().Table(childScope.TableName()).Joins(joinSql).
+			Where(fmt.Sprintf("%v.%v = ?", a.scope.Quote(relationship.JoinTable), sourceKey), primaryKeyValue).
+			Find(out)
+	}
+
+	// 查询跑在NewDB克隆出来的DB上，错误留在Find返回的DB里
+	if db != nil {
+		a.setError(db.Error)
+	}
+	return a
+}
+
+// Append 往关联里追加记录，不影响已有的关联记录
+func (a *Association) Append(values ...interface{}) *Association {
+	return a.saveAssociations(values, false)
+}
+
+// Replace 用传入的记录完全替换掉现有的关联记录
+func (a *Association) Replace(values ...interface{}) *Association {
+	return a.saveAssociations(values, true)
+}
+
+func (a *Association) saveAssociations(values []interface{}, clearExisting bool) *Association {
+	if a.Error != nil {
+		return a
+	}
+
+	switch a.field.Relationship.Kind {
+	case "has_many", "has_one":
+		a.saveHasManyOrOne(values, clearExisting)
+	case "belongs_to":
+		a.saveBelongsTo(values)
+	case "many_to_many":
+		a.saveManyToMany(values, clearExisting)
+	}
+	return a
+}
+
+// saveHasManyOrOne 把每个value的外键字段设置为父记录的主键，并更新到数据库
+func (a *Association) saveHasManyOrOne(values []interface{}, clearExisting bool) {
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+	dbName := ToSnake(relationship.ForeignKey)
+
+	if clearExisting {
+		a.clearHasManyOrOne()
+	}
+
+	for _, value := range values {
+		childScope := a.scope.New(value)
+		if field, ok := childScope.FieldByName(relationship.ForeignKey); ok {
+			a.setError(field.Set(primaryKeyValue))
+		}
+
+		childScope.Raw(fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ?",
+			childScope.Quote(childScope.TableName()), childScope.Quote(dbName), childScope.Quote(childScope.PrimaryKey())))
+		childScope.SqlVars = []interface{}{primaryKeyValue, childScope.PrimaryKeyValue()}
+		childScope.Exec()
+		a.setScopeError(childScope)
+	}
+}
+
+// clearHasManyOrOne 把所有指向父记录的外键清空，Replace/Clear之前调用
+func (a *Association) clearHasManyOrOne() {
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+	dbName := ToSnake(relationship.ForeignKey)
+
+	childScope := a.scope.New(reflect.New(preloadElemType(a.field)).Interface())
+	childScope.Raw(fmt.Sprintf("UPDATE %v SET %v = NULL WHERE %v = ?",
+		childScope.Quote(childScope.TableName()), childScope.Quote(dbName), childScope.Quote(dbName)))
+	childScope.SqlVars = []interface{}{primaryKeyValue}
+	childScope.Exec()
+	a.setScopeError(childScope)
+}
+
+// saveBelongsTo 假定value已经是持久化过的记录，把它的主键写进父记录的外键字段并保存
+func (a *Association) saveBelongsTo(values []interface{}) {
+	if len(values) == 0 {
+		return
+	}
+
+	relationship := a.field.Relationship
+	value := values[len(values)-1] // belongs_to同一时间只能指向一条记录
+	childScope := a.scope.New(value)
+	childPrimaryKeyValue := childScope.PrimaryKeyValue()
+
+	if field, ok := a.scope.FieldByName(relationship.ForeignKey); ok {
+		a.setError(field.Set(childPrimaryKeyValue))
+	}
+
+	execScope := a.scope.New(a.scope.Value)
+	execScope.Raw(fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ?",
+		execScope.Quote(a.scope.TableName()), execScope.Quote(ToSnake(relationship.ForeignKey)), execScope.Quote(a.scope.PrimaryKey())))
+	execScope.SqlVars = []interface{}{childPrimaryKeyValue, a.scope.PrimaryKeyValue()}
+	execScope.Exec()
+	a.setScopeError(execScope)
+}
+
+// saveManyToMany 往联接表插入记录
+func (a *Association) saveManyToMany(values []interface{}, clearExisting bool) {
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+	sourceKey := ToSnake(a.scope.typeName() + "Id")
+	associationKey := ToSnake(relationship.AssociationForeignKey)
+
+	if clearExisting {
+		a.clearManyToMany()
+	}
+
+	for _, value := range values {
+		childScope := a.scope.New(value)
+
+		childScope.Raw(fmt.Sprintf("INSERT INTO %v (%v,%v) VALUES (?,?)",
+			childScope.Quote(relationship.JoinTable), childScope.Quote(sourceKey), childScope.Quote(associationKey)))
+		childScope.SqlVars = []interface{}{primaryKeyValue, childScope.PrimaryKeyValue()}
+		childScope.Exec()
+		a.setScopeError(childScope)
+	}
+}
+
+// clearManyToMany 只删除联接表里的记录，不会删除被关联的记录本身
+func (a *Association) clearManyToMany() {
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+	sourceKey := ToSnake(a.scope.typeName() + "Id")
+
+	execScope := a.scope.New(nil)
+	execScope.Raw(fmt.Sprintf("DELETE FROM %v WHERE %v = ?", execScope.Quote(relationship.JoinTable), execScope.Quote(sourceKey)))
+	execScope.SqlVars = []interface{}{primaryKeyValue}
+	execScope.Exec()
+	a.setScopeError(execScope)
+}
+
+// Delete 把传入的记录从关联里移除，不影响记录本身，也不影响其它的关联记录；
+// belongs_to只把父记录内存里的外键字段置为零值，不写回数据库，也不检查传入的记录是否就是当前关联的那条
+func (a *Association) Delete(values ...interface{}) *Association {
+	if a.Error != nil || len(values) == 0 {
+		return a
+	}
+
+	relationship := a.field.Relationship
+	primaryKeyValue := a.scope.PrimaryKeyValue()
+
+	switch relationship.Kind {
+	case "has_many", "has_one":
+		dbName := ToSnake(relationship.ForeignKey)
+		for _, value := range values {
+			childScope := a.scope.New(value)
+			childScope.Raw(fmt.Sprintf("UPDATE %v SET %v = NULL WHERE %v = ? AND %v = ?",
+				childScope.Quote(childScope.TableName()), childScope.Quote(dbName), childScope.Quote(childScope.PrimaryKey()), childScope.Quote(dbName)))
+			childScope.SqlVars = []interface{}{childScope.PrimaryKeyValue(), primaryKeyValue}
+			childScope.Exec()
+			a.setScopeError(childScope)
+		}
+	case "belongs_to":
+		if field, ok := a.scope.FieldByName(relationship.ForeignKey); ok {
+			a.setError(field.Set(reflect.Zero(field.Field.Type()).Interface()))
+		}
+	case "many_to_many":
+		sourceKey := ToSnake(a.scope.typeName() + "Id")
+		associationKey := ToSnake(relationship.AssociationForeignKey)
+		for _, value := range values {
+			childScope := a.scope.New(value)
+			childScope.Raw(fmt.Sprintf("DELETE FROM %v WHERE %v = ? AND %v = ?",
+				childScope.Quote(relationship.JoinTable), childScope.Quote(sourceKey), childScope.Quote(associationKey)))
+			childScope.SqlVars = []interface{}{primaryKeyValue, childScope.PrimaryKeyValue()}
+			childScope.Exec()
+			a.setScopeError(childScope)
+		}
+	}
+	return a
+}
+
+// Clear 清空整个关联，many_to_many只会删除联接表的记录，不会删除被关联的记录；
+// belongs_to只把父记录内存里的外键字段置为零值，不写回数据库
+func (a *Association) Clear() *Association {
+	if a.Error != nil {
+		return a
+	}
+
+	switch a.field.Relationship.Kind {
+	case "has_many", "has_one":
+		a.clearHasManyOrOne()
+	case "belongs_to":
+		if field, ok := a.scope.FieldByName(a.field.Relationship.ForeignKey); ok {
+			a.setError(field.Set(reflect.Zero(field.Field.Type()).Interface()))
+		}
+	case "many_to_many":
+		a.clearManyToMany()
+	}
+	return a
+}
+
+// Count 返回当前关联的记录数
+func (a *Association) Count() int {
+	if a.Error != nil {
+		return 0
+	}
+
+	relationship := a.field.Relationship
+	var row *sql.Row
+
+	switch relationship.Kind {
+	case "has_many", "has_one":
+		dbName := ToSnake(relationship.ForeignKey)
+		childScope := a.scope.New(reflect.New(preloadElemType(a.field)).Interface())
+		row = a.scope.queryRow(fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE %v = ?",
+			a.scope.Quote(childScope.TableName()), a.scope.Quote(dbName)), a.scope.PrimaryKeyValue())
+	case "belongs_to":
+		foreignKeyValue, _ := FieldValueByName(relationship.ForeignKey, a.scope.Value)
+		childScope := a.scope.New(reflect.New(preloadElemType(a.field)).Interface())
+		row = a.scope.queryRow(fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE %v = ?",
+			a.scope.Quote(childScope.TableName()), a.scope.Quote(childScope.PrimaryKey())), foreignKeyValue)
+	case "many_to_many":
+		sourceKey := ToSnake(a.scope.typeName() + "Id")
+		row = a.scope.queryRow(fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE %v = ?",
+			a.scope.Quote(relationship.JoinTable), a.scope.Quote(sourceKey)), a.scope.PrimaryKeyValue())
+	default:
+		return 0
+	}
+
+	var count int
+	a.setError(row.Scan(&count))
+	return count
+}
@@ -0,0 +1,22 @@
+package gorm
+
+import "testing"
+
+// TestAssociationUnknownFieldReturnsError 验证column不是已建立关联的字段时，
+// (*DB).Association返回的Association带着Error，后续方法（如Append）在不触达数据库的情况下
+// 仍然保持这个Error，不会因为a.scope为nil而panic
+func TestAssociationUnknownFieldReturnsError(t *testing.T) {
+	type post struct {
+		ID int
+	}
+
+	db := newPreloadTestDB()
+	a := db.Model(&post{ID: 1}).Association("NotAField")
+	if a.Error == nil {
+		t.Fatal("expected error for unknown association field")
+	}
+
+	if got := a.Append(&post{ID: 2}); got.Error == nil {
+		t.Fatal("expected Append to keep propagating the original error instead of touching a nil scope")
+	}
+}
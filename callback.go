@@ -0,0 +1,240 @@
+package gorm
+
+// namedCallback 一个有名字、可以用Before/After定位的回调
+type namedCallback struct {
+	name    string
+	befores []string
+	afters  []string
+	fn      func(*Scope)
+}
+
+// Callback 管理create/update/delete/query/row_query五条回调链，
+// 通过(*DB).Callback()获得，第三方代码可以据此注册自己的逻辑而不需要fork本包
+type Callback struct {
+	namedCreates    []*namedCallback
+	namedUpdates    []*namedCallback
+	namedDeletes    []*namedCallback
+	namedQueries    []*namedCallback
+	namedRowQueries []*namedCallback
+
+	compiledCreates    []func(*Scope)
+	compiledUpdates    []func(*Scope)
+	compiledDeletes    []func(*Scope)
+	compiledQueries    []func(*Scope)
+	compiledRowQueries []func(*Scope)
+}
+
+// DefaultCallback 进程内默认的回调注册表，内置回调及第三方插件都注册在这里
+var DefaultCallback = &Callback{}
+
+// Callback 返回全局的回调注册表
+func (s *DB) Callback() *Callback {
+	return DefaultCallback
+}
+
+// CallbackProcessor 管理某一类操作的回调链，通过Callback.Create()/Update()/Delete()/Query()/RowQuery()获得
+type CallbackProcessor struct {
+	kind   string
+	parent *Callback
+	before string
+	after  string
+}
+
+// Create 返回create操作的回调处理器
+func (c *Callback) Create() *CallbackProcessor {
+	return &CallbackProcessor{kind: "create", parent: c}
+}
+
+// Update 返回update操作的回调处理器
+func (c *Callback) Update() *CallbackProcessor {
+	return &CallbackProcessor{kind: "update", parent: c}
+}
+
+// Delete 返回delete操作的回调处理器
+func (c *Callback) Delete() *CallbackProcessor {
+	return &CallbackProcessor{kind: "delete", parent: c}
+}
+
+// Query 返回query操作的回调处理器
+func (c *Callback) Query() *CallbackProcessor {
+	return &CallbackProcessor{kind: "query", parent: c}
+}
+
+// RowQuery 返回row_query操作的回调处理器
+func (c *Callback) RowQuery() *CallbackProcessor {
+	return &CallbackProcessor{kind: "row_query", parent: c}
+}
+
+// list 返回某一类操作当前的命名回调列表（未排序）
+func (c *Callback) list(kind string) *[]*namedCallback {
+	switch kind {
+	case "create":
+		return &c.namedCreates
+	case "update":
+		return &c.namedUpdates
+	case "delete":
+		return &c.namedDeletes
+	case "query":
+		return &c.namedQueries
+	case "row_query":
+		return &c.namedRowQueries
+	}
+	return nil
+}
+
+// compiledList 返回某一类操作排序后缓存的执行列表
+func (c *Callback) compiledList(kind string) *[]func(*Scope) {
+	switch kind {
+	case "create":
+		return &c.compiledCreates
+	case "update":
+		return &c.compiledUpdates
+	case "delete":
+		return &c.compiledDeletes
+	case "query":
+		return &c.compiledQueries
+	case "row_query":
+		return &c.compiledRowQueries
+	}
+	return nil
+}
+
+// compile 对某一类操作的回调重新做拓扑排序，生成新的执行列表
+func (c *Callback) compile(kind string) {
+	sorted := sortCallbacks(*c.list(kind))
+	fns := make([]func(*Scope), len(sorted))
+	for i, nc := range sorted {
+		fns[i] = nc.fn
+	}
+	*c.compiledList(kind) = fns
+}
+
+func (c *Callback) creates() []func(*Scope) {
+	return c.compiledCreates
+}
+
+func (c *Callback) updates() []func(*Scope) {
+	return c.compiledUpdates
+}
+
+func (c *Callback) deletes() []func(*Scope) {
+	return c.compiledDeletes
+}
+
+func (c *Callback) queries() []func(*Scope) {
+	return c.compiledQueries
+}
+
+func (c *Callback) rowQueries() []func(*Scope) {
+	return c.compiledRowQueries
+}
+
+// Before 接下来一次Register产生的回调需要排在name之前执行
+func (cp *CallbackProcessor) Before(name string) *CallbackProcessor {
+	cp.before = name
+	return cp
+}
+
+// After 接下来一次Register产生的回调需要排在name之后执行
+func (cp *CallbackProcessor) After(name string) *CallbackProcessor {
+	cp.after = name
+	return cp
+}
+
+// Register 注册一个新的命名回调，携带之前通过Before/After设置的顺序约束
+func (cp *CallbackProcessor) Register(name string, fn func(*Scope)) *CallbackProcessor {
+	list := cp.parent.list(cp.kind)
+	nc := &namedCallback{name: name, fn: fn}
+	if cp.before != "" {
+		nc.befores = append(nc.befores, cp.before)
+	}
+	if cp.after != "" {
+		nc.afters = append(nc.afters, cp.after)
+	}
+	cp.before, cp.after = "", ""
+
+	*list = removeNamedCallback(*list, name)
+	*list = append(*list, nc)
+	cp.parent.compile(cp.kind)
+	return cp
+}
+
+// Replace 替换已有回调的实现，保持它在链路里的顺序约束不变；如果name不存在则等价于Register
+func (cp *CallbackProcessor) Replace(name string, fn func(*Scope)) *CallbackProcessor {
+	for _, nc := range *cp.parent.list(cp.kind) {
+		if nc.name == name {
+			nc.fn = fn
+			cp.parent.compile(cp.kind)
+			return cp
+		}
+	}
+	return cp.Register(name, fn)
+}
+
+// Remove 从回调链里删除指定名字的回调
+func (cp *CallbackProcessor) Remove(name string) *CallbackProcessor {
+	list := cp.parent.list(cp.kind)
+	*list = removeNamedCallback(*list, name)
+	cp.parent.compile(cp.kind)
+	return cp
+}
+
+// Get 取出指定名字注册的回调函数，找不到返回nil
+func (cp *CallbackProcessor) Get(name string) func(*Scope) {
+	for _, nc := range *cp.parent.list(cp.kind) {
+		if nc.name == name {
+			return nc.fn
+		}
+	}
+	return nil
+}
+
+func removeNamedCallback(callbacks []*namedCallback, name string) []*namedCallback {
+	var kept []*namedCallback
+	for _, nc := range callbacks {
+		if nc.name != name {
+			kept = append(kept, nc)
+		}
+	}
+	return kept
+}
+
+// sortCallbacks 根据befores/afters约束对回调做拓扑排序，没有约束的保持注册顺序
+func sortCallbacks(callbacks []*namedCallback) []*namedCallback {
+	index := map[string]*namedCallback{}
+	dependsOn := map[string][]string{}
+
+	for _, nc := range callbacks {
+		index[nc.name] = nc
+	}
+	for _, nc := range callbacks {
+		dependsOn[nc.name] = append(dependsOn[nc.name], nc.afters...)
+		for _, before := range nc.befores {
+			dependsOn[before] = append(dependsOn[before], nc.name)
+		}
+	}
+
+	var sorted []*namedCallback
+	visited := map[string]bool{}
+
+	var visit func(name string, onStack map[string]bool)
+	visit = func(name string, onStack map[string]bool) {
+		if visited[name] || onStack[name] {
+			return
+		}
+		onStack[name] = true
+		for _, dep := range dependsOn[name] {
+			if _, ok := index[dep]; ok {
+				visit(dep, onStack)
+			}
+		}
+		visited[name] = true
+		sorted = append(sorted, index[name])
+	}
+
+	for _, nc := range callbacks {
+		visit(nc.name, map[string]bool{})
+	}
+
+	return sorted
+}
@@ -0,0 +1,64 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	DefaultCallback.Query().Register("gorm:query", queryCallback)
+	// gorm:preload必须在gorm:query之后，因为它需要root scope已经查出来的主键/外键值
+	DefaultCallback.Query().After("gorm:query").Register("gorm:preload", preloadCallback)
+}
+
+// queryCallback 根据Scope目前积累的条件构造SELECT语句并执行，结果写回scope.Value
+func queryCallback(scope *Scope) {
+	if _, skip := scope.InstanceGet("gorm:skip_query_callback"); skip {
+		return
+	}
+
+	defer scope.Trace(NowFunc())
+
+	destValue := scope.IndirectValue()
+	isSlice := destValue.Kind() == reflect.Slice
+
+	scope.Raw(fmt.Sprintf("SELECT * FROM %v %v", scope.QuotedTableName(), scope.CombinedConditionSql()))
+
+	rows, err := scope.queryRows(scope.Sql, scope.SqlVars...)
+	if scope.Err(err) != nil {
+		return
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if scope.Err(err) != nil {
+		return
+	}
+
+	var rowsCount int
+	for rows.Next() {
+		rowsCount++
+
+		var elem reflect.Value
+		if isSlice {
+			elem = reflect.New(destValue.Type().Elem()).Elem()
+		} else {
+			elem = destValue
+		}
+
+		elemScope := scope.New(elem.Addr().Interface())
+		scope.scan(rows, columns, elemScope.Fields())
+
+		if isSlice {
+			destValue.Set(reflect.Append(destValue, elem))
+		}
+	}
+
+	if scope.Err(rows.Err()) != nil {
+		return
+	}
+
+	if !isSlice && rowsCount == 0 {
+		scope.Err(RecordNotFound)
+	}
+}
@@ -0,0 +1,76 @@
+package gorm
+
+import "testing"
+
+// names 提取排序后回调链的name，方便断言顺序
+func names(callbacks []*namedCallback) []string {
+	result := make([]string, len(callbacks))
+	for i, nc := range callbacks {
+		result[i] = nc.name
+	}
+	return result
+}
+
+// TestSortCallbacksRespectsBeforeAfter 验证sortCallbacks按照befores/afters做拓扑排序，
+// 没有约束的回调保持注册顺序
+func TestSortCallbacksRespectsBeforeAfter(t *testing.T) {
+	callbacks := []*namedCallback{
+		{name: "c"},
+		{name: "a", befores: []string{"c"}},
+		{name: "b", afters: []string{"a"}},
+	}
+
+	sorted := sortCallbacks(callbacks)
+	order := names(sorted)
+
+	pos := map[string]int{}
+	for i, name := range order {
+		pos[name] = i
+	}
+
+	if pos["a"] >= pos["c"] {
+		t.Fatalf("expected a before c, got order %v", order)
+	}
+	if pos["b"] <= pos["a"] {
+		t.Fatalf("expected b after a, got order %v", order)
+	}
+}
+
+// TestCallbackProcessorRegisterOrdering 验证通过公开的CallbackProcessor API注册回调后，
+// Callback.compile()产出的执行链遵守Before/After约束
+func TestCallbackProcessorRegisterOrdering(t *testing.T) {
+	c := &Callback{}
+	var order []string
+
+	c.Create().Register("create_validate", func(*Scope) { order = append(order, "create_validate") })
+	c.Create().Before("create_validate").Register("create_begin_transaction", func(*Scope) { order = append(order, "create_begin_transaction") })
+	c.Create().After("create_validate").Register("create_exec", func(*Scope) { order = append(order, "create_exec") })
+
+	for _, fn := range c.creates() {
+		fn(nil)
+	}
+
+	if len(order) != 3 || order[0] != "create_begin_transaction" || order[1] != "create_validate" || order[2] != "create_exec" {
+		t.Fatalf("unexpected callback execution order: %v", order)
+	}
+}
+
+// TestCallbackProcessorReplaceAndRemove 验证Replace在原地替换实现并保留顺序约束，Remove会从链里摘掉回调
+func TestCallbackProcessorReplaceAndRemove(t *testing.T) {
+	c := &Callback{}
+	var order []string
+
+	c.Query().Register("query_first", func(*Scope) { order = append(order, "query_first") })
+	c.Query().After("query_first").Register("query_second", func(*Scope) { order = append(order, "query_second") })
+
+	c.Query().Replace("query_second", func(*Scope) { order = append(order, "query_second_replaced") })
+	c.Query().Remove("query_first")
+
+	for _, fn := range c.queries() {
+		fn(nil)
+	}
+
+	if len(order) != 1 || order[0] != "query_second_replaced" {
+		t.Fatalf("unexpected callback execution after replace/remove: %v", order)
+	}
+}
@@ -0,0 +1,31 @@
+package gorm
+
+// beginTransactionCallback 开启事务，init时注册为create/update/delete链路里的第一个回调
+func beginTransactionCallback(scope *Scope) {
+	scope.Begin()
+}
+
+// commitOrRollbackTransactionCallback 根据本次操作有没有出错提交或回滚事务，
+// init时紧跟在gorm:begin_transaction之后注册为create/update/delete链路里的最后一个回调；
+// 之后不带Before/After约束注册的回调会排在它后面，在事务提交或回滚之后才执行
+func commitOrRollbackTransactionCallback(scope *Scope) {
+	scope.CommitOrRollback()
+}
+
+func init() {
+	for _, processor := range []*CallbackProcessor{
+		DefaultCallback.Create(),
+		DefaultCallback.Update(),
+		DefaultCallback.Delete(),
+	} {
+		processor.Register("gorm:begin_transaction", beginTransactionCallback)
+	}
+
+	for _, processor := range []*CallbackProcessor{
+		DefaultCallback.Create(),
+		DefaultCallback.Update(),
+		DefaultCallback.Delete(),
+	} {
+		processor.Register("gorm:commit_or_rollback_transaction", commitOrRollbackTransactionCallback)
+	}
+}
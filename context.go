@@ -0,0 +1,31 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Context 获取当前Scope生效的context，没有通过WithContext设置过的话默认用context.Background()
+func (scope *Scope) Context() context.Context {
+	if scope.db != nil && scope.db.context != nil {
+		return scope.db.context
+	}
+	return context.Background()
+}
+
+// queryRows 优先通过QueryContext执行查询，如果底层连接没有实现sqlCommonContext就退化成普通的Query，
+// 这样旧版本的driver也能继续工作
+func (scope *Scope) queryRows(query string, args ...interface{}) (*sql.Rows, error) {
+	if db, ok := scope.DB().(sqlCommonContext); ok {
+		return db.QueryContext(scope.Context(), query, args...)
+	}
+	return scope.DB().Query(query, args...)
+}
+
+// queryRow 优先通过QueryRowContext执行查询，退化规则同queryRows
+func (scope *Scope) queryRow(query string, args ...interface{}) *sql.Row {
+	if db, ok := scope.DB().(sqlCommonContext); ok {
+		return db.QueryRowContext(scope.Context(), query, args...)
+	}
+	return scope.DB().QueryRow(query, args...)
+}
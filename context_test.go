@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// contextAwareFakeDB 实现sqlCommon和sqlCommonContext，
+// 普通方法直接panic，逼迫被测代码一定要走*Context路径
+type contextAwareFakeDB struct{}
+
+func (contextAwareFakeDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	panic("Exec should not be called when the driver supports context")
+}
+
+func (contextAwareFakeDB) Prepare(query string) (*sql.Stmt, error) {
+	panic("Prepare should not be called when the driver supports context")
+}
+
+func (contextAwareFakeDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	panic("Query should not be called when the driver supports context")
+}
+
+func (contextAwareFakeDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	panic("QueryRow should not be called when the driver supports context")
+}
+
+func (contextAwareFakeDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (contextAwareFakeDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, ctx.Err()
+}
+
+func (contextAwareFakeDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, ctx.Err()
+}
+
+func (contextAwareFakeDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func newCancelledScope() *Scope {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	db := &DB{db: contextAwareFakeDB{}, logger: defaultLogger, context: ctx, values: map[string]interface{}{}}
+	db.parent = db
+	return db.NewScope(nil)
+}
+
+// TestScopeExecContextCancelled 验证取消context后Exec会把驱动返回的context.Canceled传播到db.Error
+func TestScopeExecContextCancelled(t *testing.T) {
+	scope := newCancelledScope()
+	scope.Sql = "UPDATE users SET name = ?"
+	scope.SqlVars = []interface{}{"jinzhu"}
+
+	scope.Exec()
+
+	if !errors.Is(scope.db.Error, context.Canceled) {
+		t.Fatalf("expected scope.db.Error to be context.Canceled, got %v", scope.db.Error)
+	}
+}
+
+// TestScopeQueryRowsContextCancelled 验证取消context后queryRows会走QueryContext并返回context.Canceled
+func TestScopeQueryRowsContextCancelled(t *testing.T) {
+	scope := newCancelledScope()
+
+	_, err := scope.queryRows("SELECT * FROM users")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
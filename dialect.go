@@ -0,0 +1,121 @@
+package gorm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Dialect 数据库方言接口，屏蔽不同数据库在SQL语法上的差异，
+// 第三方驱动（ClickHouse、SQL Server、CockroachDB、TiDB等）可以实现这个接口并通过RegisterDialect注册，
+// 不需要fork本包
+type Dialect interface {
+	// Quote 给标识符（表名、列名）加上引用符号
+	Quote(key string) string
+	// BinVar 返回第i个绑定变量的占位符，Scope.Raw会将"$$"替换成真正的占位符
+	BinVar(i int) string
+	// DataTypeOf 返回field对应的数据库列类型
+	DataTypeOf(field *Field) string
+	// HasTable 判断tableName对应的表是否存在
+	HasTable(scope *Scope, tableName string) bool
+	// HasColumn 判断tableName表里是否存在columnName列
+	HasColumn(scope *Scope, tableName string, columnName string) bool
+	// HasIndex 判断tableName表里是否存在indexName索引
+	HasIndex(scope *Scope, tableName string, indexName string) bool
+	// CurrentDatabase 返回当前连接所在的数据库名
+	CurrentDatabase(scope *Scope) string
+	// ReturningStr 返回INSERT语句需要追加的RETURNING子句，不支持的方言返回空字符串
+	ReturningStr(tableName, key string) string
+	// SelectFromDummyTable 返回不带FROM子句时需要补充的占位表，不需要的方言返回空字符串
+	SelectFromDummyTable() string
+}
+
+// commonDialect 默认方言实现，未注册专门方言的driver都使用它
+type commonDialect struct{}
+
+func (commonDialect) Quote(key string) string {
+	return `"` + key + `"`
+}
+
+func (commonDialect) BinVar(i int) string {
+	return "$$"
+}
+
+func (commonDialect) DataTypeOf(field *Field) string {
+	if field.IsTime() {
+		return "TIMESTAMP"
+	}
+
+	switch field.Field.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "INTEGER"
+	case reflect.Int64, reflect.Uint64:
+		return "BIGINT"
+	case reflect.Float32, reflect.Float64:
+		return "FLOAT"
+	default:
+		return "VARCHAR(255)"
+	}
+}
+
+func (commonDialect) HasTable(scope *Scope, tableName string) bool {
+	var count int
+	scope.queryRow("SELECT count(*) FROM information_schema.tables WHERE table_name = ?", tableName).Scan(&count)
+	return count > 0
+}
+
+func (commonDialect) HasColumn(scope *Scope, tableName string, columnName string) bool {
+	var count int
+	scope.queryRow("SELECT count(*) FROM information_schema.columns WHERE table_name = ? AND column_name = ?", tableName, columnName).Scan(&count)
+	return count > 0
+}
+
+func (commonDialect) HasIndex(scope *Scope, tableName string, indexName string) bool {
+	var count int
+	scope.queryRow("SELECT count(*) FROM information_schema.statistics WHERE table_name = ? AND index_name = ?", tableName, indexName).Scan(&count)
+	return count > 0
+}
+
+func (commonDialect) CurrentDatabase(scope *Scope) string {
+	var name string
+	scope.queryRow("SELECT database()").Scan(&name)
+	return name
+}
+
+func (commonDialect) ReturningStr(tableName, key string) string {
+	return ""
+}
+
+func (commonDialect) SelectFromDummyTable() string {
+	return ""
+}
+
+// dialectsMapMu 保护dialectsMap的并发读写
+var dialectsMapMu sync.RWMutex
+
+// dialectsMap 已知的driver名字到Dialect实现的映射
+var dialectsMap = map[string]Dialect{}
+
+// RegisterDialect 注册driver名字对应的Dialect实现，第三方数据库驱动据此作为独立的Go module发布，
+// 不需要fork gorm本身；name通常跟database/sql驱动名一致
+func RegisterDialect(name string, dialect Dialect) {
+	dialectsMapMu.Lock()
+	defer dialectsMapMu.Unlock()
+	dialectsMap[name] = dialect
+}
+
+// GetDialect 按名字查找已经注册的Dialect，ok表示是否找到
+func GetDialect(name string) (dialect Dialect, ok bool) {
+	dialectsMapMu.RLock()
+	defer dialectsMapMu.RUnlock()
+	dialect, ok = dialectsMap[name]
+	return
+}
+
+// iLikeDialect 支持大小写不敏感ILIKE语法的方言可以实现这个接口，
+// 否则lookup里的icontains等操作符退化成UPPER(...) LIKE UPPER(...)
+type iLikeDialect interface {
+	SupportsILike() bool
+}
@@ -0,0 +1,6 @@
+package gorm
+
+import "errors"
+
+// RecordNotFound 当查询没有匹配到任何记录时返回的错误
+var RecordNotFound = errors.New("record not found")
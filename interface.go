@@ -1,6 +1,9 @@
 package gorm
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 type sqlCommon interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
@@ -9,11 +12,25 @@ type sqlCommon interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
+// sqlCommonContext 跟sqlCommon平行的接口，支持传入context取消/超时查询，
+// *sql.DB和*sql.Tx在现代Go版本里都实现了这个接口
+type sqlCommonContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // 事务接口
 type sqlDb interface {
 	Begin() (*sql.Tx, error)
 }
 
+// sqlDbContext 跟sqlDb平行的接口，开启事务时带上context
+type sqlDbContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
 // 事务接口
 type sqlTx interface {
 	Commit() error
@@ -0,0 +1,23 @@
+package gorm
+
+import (
+	"log"
+	"os"
+)
+
+// logger 日志输出接口，跟DB.logMode配合使用
+type logger interface {
+	Print(v ...interface{})
+}
+
+// logWriter 默认日志实现，直接输出到标准输出
+type logWriter struct {
+	*log.Logger
+}
+
+func (l *logWriter) Print(v ...interface{}) {
+	l.Println(v...)
+}
+
+// defaultLogger 默认的日志记录器
+var defaultLogger = &logWriter{log.New(os.Stdout, "\r\n", 0)}
@@ -0,0 +1,95 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// lookupOperators beego ORM风格的字段查找后缀
+var lookupOperators = map[string]bool{
+	"exact": true, "iexact": true,
+	"contains": true, "icontains": true,
+	"startswith": true, "istartswith": true,
+	"endswith": true, "iendswith": true,
+	"gt": true, "gte": true,
+	"lt": true, "lte": true,
+	"in": true, "isnull": true,
+}
+
+// parseLookupKey 把"age__gte"这样的key拆成列名和操作符，
+// 如果最后一段不是已知操作符，就把整个key当作列名，操作符为exact，兼容老的用法
+func parseLookupKey(key string) (column, operator string) {
+	if idx := strings.LastIndex(key, "__"); idx != -1 {
+		if candidate := key[idx+2:]; lookupOperators[candidate] {
+			return key[:idx], candidate
+		}
+	}
+	return key, "exact"
+}
+
+// buildLookupCondition 把列名、操作符、值构造成SQL片段
+func (scope *Scope) buildLookupCondition(column, operator string, value interface{}) string {
+	quotedColumn := scope.Quote(ToSnake(column))
+
+	switch operator {
+	case "gt":
+		return fmt.Sprintf("(%v > %v)", quotedColumn, scope.AddToVars(value))
+	case "gte":
+		return fmt.Sprintf("(%v >= %v)", quotedColumn, scope.AddToVars(value))
+	case "lt":
+		return fmt.Sprintf("(%v < %v)", quotedColumn, scope.AddToVars(value))
+	case "lte":
+		return fmt.Sprintf("(%v <= %v)", quotedColumn, scope.AddToVars(value))
+	case "isnull":
+		if isTrue, ok := value.(bool); ok && !isTrue {
+			return fmt.Sprintf("(%v IS NOT NULL)", quotedColumn)
+		}
+		return fmt.Sprintf("(%v IS NULL)", quotedColumn)
+	case "in":
+		return scope.buildInCondition(quotedColumn, value)
+	case "contains":
+		return scope.buildLikeCondition(quotedColumn, "%"+fmt.Sprint(value)+"%", false)
+	case "icontains":
+		return scope.buildLikeCondition(quotedColumn, "%"+fmt.Sprint(value)+"%", true)
+	case "startswith":
+		return scope.buildLikeCondition(quotedColumn, fmt.Sprint(value)+"%", false)
+	case "istartswith":
+		return scope.buildLikeCondition(quotedColumn, fmt.Sprint(value)+"%", true)
+	case "endswith":
+		return scope.buildLikeCondition(quotedColumn, "%"+fmt.Sprint(value), false)
+	case "iendswith":
+		return scope.buildLikeCondition(quotedColumn, "%"+fmt.Sprint(value), true)
+	case "iexact":
+		return scope.buildLikeCondition(quotedColumn, fmt.Sprint(value), true)
+	default: // exact，以及任何未知后缀
+		return fmt.Sprintf("(%v = %v)", quotedColumn, scope.AddToVars(value))
+	}
+}
+
+// buildInCondition 把slice参数展开成IN(...)，非slice参数退化成等值比较
+func (scope *Scope) buildInCondition(quotedColumn string, value interface{}) string {
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	if reflectValue.Kind() != reflect.Slice {
+		return fmt.Sprintf("(%v = %v)", quotedColumn, scope.AddToVars(value))
+	}
+
+	var placeholders []string
+	for i := 0; i < reflectValue.Len(); i++ {
+		placeholders = append(placeholders, scope.AddToVars(reflectValue.Index(i).Interface()))
+	}
+	return fmt.Sprintf("(%v IN (%v))", quotedColumn, strings.Join(placeholders, ","))
+}
+
+// buildLikeCondition 构造模糊匹配条件，大小写不敏感时优先使用方言的ILIKE，
+// 否则退化成UPPER(column) LIKE UPPER(pattern)
+func (scope *Scope) buildLikeCondition(quotedColumn, pattern string, caseInsensitive bool) string {
+	if !caseInsensitive {
+		return fmt.Sprintf("(%v LIKE %v)", quotedColumn, scope.AddToVars(pattern))
+	}
+
+	if dialect, ok := scope.Dialect().(iLikeDialect); ok && dialect.SupportsILike() {
+		return fmt.Sprintf("(%v ILIKE %v)", quotedColumn, scope.AddToVars(pattern))
+	}
+	return fmt.Sprintf("(UPPER(%v) LIKE UPPER(%v))", quotedColumn, scope.AddToVars(pattern))
+}
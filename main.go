@@ -0,0 +1,193 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DB GORM的操作入口，所有的数据库操作都是通过DB的实例完成的
+type DB struct {
+	db             sqlCommon
+	parent         *DB
+	search         *search
+	logMode        int
+	logger         logger
+	dialect        Dialect
+	singularTable  bool
+	tagIdentifier  string
+	namingStrategy *NamingStrategy
+	context        context.Context
+	values         map[string]interface{}
+
+	Value        interface{}
+	Error        error
+	RowsAffected int64
+}
+
+// Open 根据dialect打开一个数据库连接，source可以是DSN字符串，也可以是已经建立好的*sql.DB
+func Open(dialect string, args ...interface{}) (*DB, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid database source, args could not be empty")
+	}
+
+	var dbSQL sqlCommon
+	var err error
+
+	switch source := args[0].(type) {
+	case string:
+		dbSQL, err = sql.Open(dialect, source)
+	case sqlCommon:
+		dbSQL = source
+	default:
+		return nil, fmt.Errorf("invalid database source: %v", args[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{
+		db:            dbSQL,
+		logger:        defaultLogger,
+		values:        map[string]interface{}{},
+		tagIdentifier: "sql",
+	}
+	db.parent = db
+
+	if d, ok := GetDialect(dialect); ok {
+		db.dialect = d
+	} else {
+		db.dialect = &commonDialect{}
+	}
+
+	return db, nil
+}
+
+// Close 关闭数据库连接
+func (s *DB) Close() error {
+	if db, ok := s.parent.db.(*sql.DB); ok {
+		return db.Close()
+	}
+	return fmt.Errorf("can't close current db")
+}
+
+// New 创建一个跟当前DB共享连接和配置，但没有任何查找条件的新DB
+func (s *DB) New() *DB {
+	clone := s.clone()
+	clone.search = nil
+	clone.Value = nil
+	return clone
+}
+
+// NewRecord 判断value是否为新记录（主键为空）
+func (s *DB) NewRecord(value interface{}) bool {
+	return s.NewScope(value).PrimaryKeyZero()
+}
+
+// WithContext 把ctx绑定到克隆出来的DB上，后续的查询、事务都会带上这个context，
+// 驱动可以据此取消或者在超时后中断正在执行的SQL
+func (s *DB) WithContext(ctx context.Context) *DB {
+	clone := s.clone()
+	clone.context = ctx
+	return clone
+}
+
+// Set 在克隆出来的DB上设置一个跟随clone传播的变量，不影响原来的DB
+func (s *DB) Set(name string, value interface{}) *DB {
+	clone := s.clone()
+	clone.InstantSet(name, value)
+	return clone
+}
+
+// InstantSet 直接在当前DB上设置变量，不会克隆，调用方需要自行保证不会影响到别处共享的DB
+func (s *DB) InstantSet(name string, value interface{}) *DB {
+	s.values[name] = value
+	return s
+}
+
+// Get 取出之前通过Set/InstantSet设置的变量
+func (s *DB) Get(name string) (interface{}, bool) {
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// Where 添加查找条件，query可以是struct、map、string
+func (s *DB) Where(query interface{}, args ...interface{}) *DB {
+	clone := s.clone()
+	clone.search.where(query, args...)
+	return clone
+}
+
+// Joins 添加JOIN子句
+func (s *DB) Joins(query string) *DB {
+	clone := s.clone()
+	clone.search.joinConditions = append(clone.search.joinConditions, query)
+	return clone
+}
+
+// Table 指定表名
+func (s *DB) Table(name string) *DB {
+	clone := s.clone()
+	clone.search.TableName = name
+	return clone
+}
+
+// Model 指定操作对象的类型，Value为空但需要表名、字段信息时使用
+func (s *DB) Model(value interface{}) *DB {
+	clone := s.clone()
+	clone.Value = value
+	return clone
+}
+
+// Order 指定排序方式
+func (s *DB) Order(value string) *DB {
+	clone := s.clone()
+	clone.search.orders = append(clone.search.orders, value)
+	return clone
+}
+
+// Limit 指定返回的记录数上限
+func (s *DB) Limit(limit interface{}) *DB {
+	clone := s.clone()
+	clone.search.limit = limit
+	return clone
+}
+
+// Offset 指定跳过的记录数
+func (s *DB) Offset(offset interface{}) *DB {
+	clone := s.clone()
+	clone.search.offset = offset
+	return clone
+}
+
+// Preload 预加载关联数据，column为关联的字段名，支持使用"."连接的嵌套关联，如"Orders.Items"
+// conditions可以追加到关联查询的WHERE条件中
+func (s *DB) Preload(column string, conditions ...interface{}) *DB {
+	clone := s.clone()
+	clone.search.preloadAssociation(column, conditions...)
+	return clone
+}
+
+// Find 根据现有的条件查找匹配的记录并赋值给out
+func (s *DB) Find(out interface{}, where ...interface{}) *DB {
+	newScope := s.clone().NewScope(out)
+	if len(where) > 0 {
+		newScope.Search.where(where[0], where[1:]...)
+	}
+	newScope.callCallbacks(DefaultCallback.queries())
+	return newScope.db
+}
+
+// First 查找第一条匹配的记录，并按主键排序
+func (s *DB) First(out interface{}, where ...interface{}) *DB {
+	newScope := s.clone().NewScope(out)
+	newScope.Search.limit = 1
+	if orderField := newScope.PrimaryKey(); orderField != "" {
+		newScope.Search.orders = append([]string{orderField}, newScope.Search.orders...)
+	}
+	if len(where) > 0 {
+		newScope.Search.where(where[0], where[1:]...)
+	}
+	newScope.callCallbacks(DefaultCallback.queries())
+	return newScope.db
+}
@@ -0,0 +1,83 @@
+package gorm
+
+import "regexp"
+
+// NamingStrategy 让用户自定义表名、列名、联接表名的生成规则，
+// 替换掉原来写死在Scope.TableName里的正则复数化逻辑
+type NamingStrategy struct {
+	TableName     func(name string) string
+	ColumnName    func(name string) string
+	JoinTableName func(name string) string
+}
+
+var pluralMapKeys = []*regexp.Regexp{regexp.MustCompile("ch$"), regexp.MustCompile("ss$"), regexp.MustCompile("sh$"), regexp.MustCompile("day$"), regexp.MustCompile("y$"), regexp.MustCompile("x$"), regexp.MustCompile("([^s])s?$")}
+var pluralMapValues = []string{"ches", "sses", "shes", "days", "ies", "xes", "${1}s"}
+
+// defaultNamingStrategy 跟以前写死的行为完全一致：ToSnake之后做英文复数变换
+var defaultNamingStrategy = &NamingStrategy{
+	TableName: func(name string) string {
+		str := ToSnake(name)
+		for index, reg := range pluralMapKeys {
+			if reg.MatchString(str) {
+				return reg.ReplaceAllString(str, pluralMapValues[index])
+			}
+		}
+		return str
+	},
+	ColumnName:    ToSnake,
+	JoinTableName: ToSnake,
+}
+
+// SingularNamingStrategy 表名跟字段名一样做snake_case处理，不做复数变换，
+// 适合已经有自己命名习惯、或者非英文schema的场景
+var SingularNamingStrategy = &NamingStrategy{
+	TableName:     ToSnake,
+	ColumnName:    ToSnake,
+	JoinTableName: ToSnake,
+}
+
+// namingStrategy 进程内全局生效的命名策略，默认跟以前的行为保持一致
+var namingStrategy = defaultNamingStrategy
+
+// AddNamingStrategy 替换全局默认的命名策略，会影响之后所有没有单独调用过SetNamingStrategy、也没有打开singularTable的DB
+func AddNamingStrategy(ns *NamingStrategy) {
+	namingStrategy = ns
+}
+
+// SetNamingStrategy 为当前连接单独指定命名策略
+func (s *DB) SetNamingStrategy(ns *NamingStrategy) *DB {
+	s.parent.namingStrategy = ns
+	return s
+}
+
+// activeNamingStrategy 获取当前Scope应该使用的命名策略：
+// 优先使用DB单独设置的，其次是singularTable这个历史开关，最后才是全局默认策略
+func (scope *Scope) activeNamingStrategy() *NamingStrategy {
+	if scope.db != nil {
+		if scope.db.parent.namingStrategy != nil {
+			return scope.db.parent.namingStrategy
+		}
+		if scope.db.parent.singularTable {
+			return SingularNamingStrategy
+		}
+	}
+	return namingStrategy
+}
+
+// ToDBName 使用AddNamingStrategy设置的全局默认命名策略把Go标识符转换成数据库列名；
+// 注意这里看到的是全局默认策略，某个连接通过(*DB).SetNamingStrategy单独指定的策略不会反映在这里
+func ToDBName(name string) string {
+	return namingStrategy.ColumnName(name)
+}
+
+// ToTableName 使用AddNamingStrategy设置的全局默认命名策略把类型名转换成表名；
+// 注意这里看到的是全局默认策略，某个连接通过(*DB).SetNamingStrategy单独指定的策略不会反映在这里
+func ToTableName(name string) string {
+	return namingStrategy.TableName(name)
+}
+
+// ToColumnName 使用AddNamingStrategy设置的全局默认命名策略把字段名转换成列名；
+// 注意这里看到的是全局默认策略，某个连接通过(*DB).SetNamingStrategy单独指定的策略不会反映在这里
+func ToColumnName(name string) string {
+	return namingStrategy.ColumnName(name)
+}
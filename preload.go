@@ -0,0 +1,264 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// preloadCallback 处理Preload方法记录下来的所有关联，在gorm:query之后执行
+func preloadCallback(scope *Scope) {
+	if scope.Value == nil || len(scope.Search.preload) == 0 {
+		return
+	}
+
+	for _, preload := range scope.Search.preload {
+		if scope.HasError() {
+			return
+		}
+		preloadAssociation(scope, preload.schema, preload.conditions)
+	}
+}
+
+// preloadAssociation 加载schema指定的关联，schema支持"Orders.Items"这样的嵌套写法。
+// conditions只会用在schema的最后一级（"Orders.Items"里的"Items"），中间层级（"Orders"）不会
+// 被传入的conditions过滤；嵌套的关联一定在assign之前完成加载，这样assign把子记录拷贝进父记录
+// 字段的时候，子记录自己的关联也已经准备好了，不会因为值拷贝而丢失
+func preloadAssociation(scope *Scope, schema string, conditions []interface{}) {
+	parts := strings.SplitN(schema, ".", 2)
+	fieldName := parts[0]
+	hasNested := len(parts) == 2
+
+	field, ok := scope.fieldByNameForPreload(fieldName)
+	if !ok || field.Relationship == nil {
+		scope.Err(fmt.Errorf("can't preload field %v for %v", fieldName, scope.typeName()))
+		return
+	}
+
+	var ownConditions []interface{}
+	if !hasNested {
+		ownConditions = conditions
+	}
+
+	var childScope *Scope
+	var assign func()
+	switch field.Relationship.Kind {
+	case "has_many", "has_one":
+		childScope, assign = preloadHasManyOrOne(scope, field, ownConditions)
+	case "belongs_to":
+		childScope, assign = preloadBelongsTo(scope, field, ownConditions)
+	case "many_to_many":
+		childScope, assign = preloadManyToMany(scope, field, ownConditions)
+	default:
+		scope.Err(fmt.Errorf("unsupported relationship kind %v for preload", field.Relationship.Kind))
+		return
+	}
+
+	if childScope != nil && hasNested {
+		preloadAssociation(childScope, parts[1], conditions)
+	}
+	if assign != nil {
+		assign()
+	}
+}
+
+// fieldByNameForPreload 跟FieldByName类似，但是兼容Scope.Value是slice的情况
+func (scope *Scope) fieldByNameForPreload(name string) (*Field, bool) {
+	clone := scope
+	if scope.IndirectValue().Kind() == reflect.Slice {
+		clone = scope.New(reflect.New(scope.IndirectValue().Type().Elem()).Elem().Interface())
+	}
+	return clone.FieldByName(name)
+}
+
+// preloadElemType 取出关联字段实际保存的元素类型（去掉slice、指针）
+func preloadElemType(field *Field) reflect.Type {
+	elemType := field.Field.Type()
+	if elemType.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	return elemType
+}
+
+// preloadFetch 根据查询条件把关联表的记录查出来，返回保存结果的Scope（元素为elemType的slice）
+func preloadFetch(scope *Scope, elemType reflect.Type, query string, args []interface{}, conditions []interface{}) *Scope {
+	results := reflect.New(reflect.SliceOf(elemType)).Elem()
+
+	db := scope.NewDB().Table(scope.New(reflect.New(elemType).Interface()).TableName())
+	db = db.Where(query, args...)
+	for _, condition := range conditions {
+		db = db.Where(condition)
+	}
+	db.Find(results.Addr().Interface())
+
+	return scope.New(results.Addr().Interface())
+}
+
+// preloadHasManyOrOne 加载has_many/has_one关联，用父表的主键去匹配子表的外键。
+// 返回查到的子记录Scope和一个延迟的assign闭包，调用方需要等子记录自己的嵌套关联都加载完之后再调assign，
+// 否则assign把子记录值拷贝进父记录字段时，后加载的嵌套关联就丢了
+func preloadHasManyOrOne(scope *Scope, field *Field, conditions []interface{}) (*Scope, func()) {
+	relationship := field.Relationship
+	primaryKeys := scope.getColumnAsArray(scope.PrimaryKeyField().Name)
+	elemType := preloadElemType(field)
+	dbName := ToSnake(relationship.ForeignKey)
+
+	childScope := preloadFetch(scope, elemType, fmt.Sprintf("%v IN (?)", scope.Quote(dbName)), []interface{}{primaryKeys}, conditions)
+
+	assign := func() {
+		assignPreloaded(scope, field, childScope, scope.PrimaryKeyField().Name, relationship.ForeignKey, relationship.Kind == "has_one")
+	}
+	return childScope, assign
+}
+
+// preloadBelongsTo 加载belongs_to关联，用父表的外键去匹配子表的主键
+func preloadBelongsTo(scope *Scope, field *Field, conditions []interface{}) (*Scope, func()) {
+	relationship := field.Relationship
+	foreignKeys := scope.getColumnAsArray(relationship.ForeignKey)
+	elemType := preloadElemType(field)
+
+	primaryKeyName := scope.New(reflect.New(elemType).Interface()).PrimaryKeyField().Name
+	dbName := ToSnake(primaryKeyName)
+
+	childScope := preloadFetch(scope, elemType, fmt.Sprintf("%v IN (?)", scope.Quote(dbName)), []interface{}{foreignKeys}, conditions)
+
+	assign := func() {
+		assignPreloaded(scope, field, childScope, relationship.ForeignKey, primaryKeyName, true)
+	}
+	return childScope, assign
+}
+
+// preloadManyToMany 加载many_to_many关联，先查联接表拿到关联ID，再查关联表本身
+func preloadManyToMany(scope *Scope, field *Field, conditions []interface{}) (*Scope, func()) {
+	relationship := field.Relationship
+	elemType := preloadElemType(field)
+
+	primaryKeys := scope.getColumnAsArray(scope.PrimaryKeyField().Name)
+	sourceKey := ToSnake(scope.typeName() + "Id")
+	associationKey := ToSnake(relationship.AssociationForeignKey)
+
+	joinPlaceholders := strings.TrimSuffix(strings.Repeat("?,", len(primaryKeys)), ",")
+	joinRows, err := scope.queryRows(
+		fmt.Sprintf("SELECT * FROM %v WHERE %v IN (%v)", scope.Quote(relationship.JoinTable), scope.Quote(sourceKey), joinPlaceholders),
+		primaryKeys...,
+	)
+	if scope.Err(err) != nil {
+		return nil, nil
+	}
+	defer joinRows.Close()
+
+	columns, err := joinRows.Columns()
+	if scope.Err(err) != nil {
+		return nil, nil
+	}
+
+	joinPairs := map[string][]interface{}{}
+	var associationIds []interface{}
+	for joinRows.Next() {
+		values := make([]interface{}, len(columns))
+		for i := range values {
+			var v interface{}
+			values[i] = &v
+		}
+		if scope.Err(joinRows.Scan(values...)) != nil {
+			return nil, nil
+		}
+
+		row := map[string]interface{}{}
+		for i, column := range columns {
+			row[column] = *(values[i].(*interface{}))
+		}
+
+		sourceId := row[sourceKey]
+		associationId := row[associationKey]
+		joinPairs[fmt.Sprint(sourceId)] = append(joinPairs[fmt.Sprint(sourceId)], associationId)
+		associationIds = append(associationIds, associationId)
+	}
+
+	childPrimaryKeyName := scope.New(reflect.New(elemType).Interface()).PrimaryKeyField().Name
+	childScope := preloadFetch(scope, elemType, fmt.Sprintf("%v IN (?)", scope.Quote(ToSnake(childPrimaryKeyName))), []interface{}{associationIds}, conditions)
+
+	assign := func() {
+		assignManyToMany(scope, field, childScope, joinPairs, childPrimaryKeyName)
+	}
+	return childScope, assign
+}
+
+// assignPreloaded 把查出来的关联结果按外键值分配给父记录对应的字段
+func assignPreloaded(scope *Scope, field *Field, childScope *Scope, parentKeyName, childKeyName string, single bool) {
+	if childScope == nil {
+		return
+	}
+
+	results := childScope.IndirectValue()
+
+	forEachRow(scope, func(rowScope *Scope) {
+		parentKeyValue, _ := FieldValueByName(parentKeyName, rowScope.Value)
+		rowField, _ := rowScope.FieldByName(field.Name)
+
+		var matched reflect.Value
+		if results.Kind() == reflect.Slice {
+			matched = reflect.MakeSlice(results.Type(), 0, 0)
+			for i := 0; i < results.Len(); i++ {
+				elem := results.Index(i)
+				childKeyValue, _ := FieldValueByName(childKeyName, elem.Addr().Interface())
+				if fmt.Sprint(childKeyValue) == fmt.Sprint(parentKeyValue) {
+					matched = reflect.Append(matched, elem)
+				}
+			}
+		}
+
+		if single {
+			if matched.Len() > 0 {
+				rowField.Set(matched.Index(0).Interface())
+			}
+		} else {
+			rowField.Set(matched.Interface())
+		}
+	})
+}
+
+// assignManyToMany 根据联接表查出来的id对把多对多关联结果分配给父记录，
+// childPrimaryKeyName是关联表(子表)主键字段的名字，joinPairs用fmt.Sprint后的主键值作为key，
+// 因为驱动扫描出来的联接表值（如int64）跟Go结构体字段的主键值（如int/uint）类型不一定一致
+func assignManyToMany(scope *Scope, field *Field, childScope *Scope, joinPairs map[string][]interface{}, childPrimaryKeyName string) {
+	if childScope == nil {
+		return
+	}
+
+	results := childScope.IndirectValue()
+
+	forEachRow(scope, func(rowScope *Scope) {
+		primaryKeyValue := rowScope.PrimaryKeyValue()
+		rowField, _ := rowScope.FieldByName(field.Name)
+
+		associationIds := joinPairs[fmt.Sprint(primaryKeyValue)]
+		matched := reflect.MakeSlice(results.Type(), 0, 0)
+		for i := 0; i < results.Len(); i++ {
+			elem := results.Index(i)
+			elemPkValue, _ := FieldValueByName(childPrimaryKeyName, elem.Addr().Interface())
+			for _, associationId := range associationIds {
+				if fmt.Sprint(elemPkValue) == fmt.Sprint(associationId) {
+					matched = reflect.Append(matched, elem)
+				}
+			}
+		}
+
+		rowField.Set(matched.Interface())
+	})
+}
+
+// forEachRow 对scope.Value中的每一行（单个struct或slice的每个元素）调用fn，fn拿到的Scope可以正确寻址
+func forEachRow(scope *Scope, fn func(rowScope *Scope)) {
+	values := scope.IndirectValue()
+	if values.Kind() == reflect.Slice {
+		for i := 0; i < values.Len(); i++ {
+			fn(scope.New(values.Index(i).Addr().Interface()))
+		}
+	} else {
+		fn(scope.New(scope.Value))
+	}
+}
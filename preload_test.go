@@ -0,0 +1,85 @@
+package gorm
+
+import (
+	"fmt"
+	"testing"
+)
+
+// newPreloadTestDB 构造一个只够做反射/字段解析的DB，不会真正执行SQL
+func newPreloadTestDB() *DB {
+	db := &DB{logger: defaultLogger, values: map[string]interface{}{}}
+	db.parent = db
+	return db
+}
+
+// TestAssignManyToManyKeyTypeMismatch 验证joinPairs用fmt.Sprint后的字符串做key，
+// 不会因为驱动扫描出来的联接表值（这里模拟成int64）和父记录主键Go类型（这里是int）不一致而丢失匹配
+func TestAssignManyToManyKeyTypeMismatch(t *testing.T) {
+	type category struct {
+		ID   int
+		Name string
+	}
+	type post struct {
+		ID         int
+		Categories []category
+	}
+
+	db := newPreloadTestDB()
+	parentScope := db.NewScope(&post{ID: 1})
+	field, ok := parentScope.FieldByName("Categories")
+	if !ok {
+		t.Fatal("Categories field not found")
+	}
+
+	joinPairs := map[string][]interface{}{
+		fmt.Sprint(int64(1)): {int64(100)},
+	}
+
+	categories := []category{{ID: 100, Name: "tech"}, {ID: 200, Name: "life"}}
+	childScope := db.NewScope(&categories)
+
+	assignManyToMany(parentScope, field, childScope, joinPairs, "ID")
+
+	p := parentScope.Value.(*post)
+	if len(p.Categories) != 1 || p.Categories[0].ID != 100 {
+		t.Fatalf("expected Categories to contain the matched category, got %+v", p.Categories)
+	}
+}
+
+// TestAssignPreloadedNestedOrder 验证preloadAssociation处理嵌套schema（如"Orders.Items"）时，
+// 只要子记录的嵌套关联在assign之前完成，assign把子记录拷贝进父记录字段时就不会丢失嵌套数据
+func TestAssignPreloadedNestedOrder(t *testing.T) {
+	type item struct {
+		ID      int
+		OrderID int
+	}
+	type order struct {
+		ID     int
+		UserID int
+		Items  []item
+	}
+	type user struct {
+		ID     int
+		Orders []order
+	}
+
+	db := newPreloadTestDB()
+	parentScope := db.NewScope(&user{ID: 1})
+	field, ok := parentScope.FieldByName("Orders")
+	if !ok {
+		t.Fatal("Orders field not found")
+	}
+
+	orders := []order{{ID: 10, UserID: 1}}
+	childScope := db.NewScope(&orders)
+
+	// 模拟preloadAssociation递归处理"Items"时已经把嵌套关联写进了子记录
+	orders[0].Items = []item{{ID: 100, OrderID: 10}}
+
+	assignPreloaded(parentScope, field, childScope, "ID", "UserID", false)
+
+	u := parentScope.Value.(*user)
+	if len(u.Orders) != 1 || len(u.Orders[0].Items) != 1 || u.Orders[0].Items[0].ID != 100 {
+		t.Fatalf("expected nested Items to survive the copy into the parent, got %+v", u.Orders)
+	}
+}
@@ -1,6 +1,7 @@
 package gorm
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -8,7 +9,6 @@ import (
 	"time"
 
 	"reflect"
-	"regexp"
 )
 
 // SQL 作用域，存放一个特定场景（可能有多个SQL操作）的数据
@@ -234,9 +234,6 @@ func (scope *Scope) AddToVars(value interface{}) string {
 }
 
 // TableName get table name
-var pluralMapKeys = []*regexp.Regexp{regexp.MustCompile("ch$"), regexp.MustCompile("ss$"), regexp.MustCompile("sh$"), regexp.MustCompile("day$"), regexp.MustCompile("y$"), regexp.MustCompile("x$"), regexp.MustCompile("([^s])s?$")}
-var pluralMapValues = []string{"ches", "sses", "shes", "days", "ies", "xes", "${1}s"}
-
 func (scope *Scope) TableName() string {
 	if scope.Search != nil && len(scope.Search.TableName) > 0 {
 		return scope.Search.TableName
@@ -263,17 +260,7 @@ func (scope *Scope) TableName() string {
 			}
 		}
 
-		str := ToSnake(data.Type().Name())
-
-		if scope.db == nil || !scope.db.parent.singularTable {
-			for index, reg := range pluralMapKeys {
-				if reg.MatchString(str) {
-					return reg.ReplaceAllString(str, pluralMapValues[index])
-				}
-			}
-		}
-
-		return str
+		return scope.activeNamingStrategy().TableName(data.Type().Name())
 	}
 }
 
@@ -336,7 +323,7 @@ func (scope *Scope) fieldFromStruct(fieldStruct reflect.StructField, withRelatio
 	if value, ok := settings["COLUMN"]; ok {
 		field.DBName = value
 	} else {
-		field.DBName = ToSnake(fieldStruct.Name)
+		field.DBName = scope.activeNamingStrategy().ColumnName(fieldStruct.Name)
 	}
 
 	// 数据库系统特定标签
@@ -399,6 +386,9 @@ func (scope *Scope) fieldFromStruct(fieldStruct reflect.StructField, withRelatio
 
 				if many2many != "" {
 					field.Relationship.Kind = "many_to_many"
+					if many2many == "MANY2MANY" { // 标签没有显式指定联接表名，使用命名策略生成默认值
+						field.Relationship.JoinTable = scope.activeNamingStrategy().JoinTableName(scopeTyp.Name() + typ.Name())
+					}
 				}
 			} else {
 				field.IsNormal = true
@@ -410,7 +400,6 @@ func (scope *Scope) fieldFromStruct(fieldStruct reflect.StructField, withRelatio
 				var fields []*Field
 				if field.Field.CanAddr() { // 可寻址？？  递归获取
 					for _, field := range scope.New(field.Field.Addr().Interface()).Fields() {
-						field.DBName = field.DBName
 						fields = append(fields, field)
 					}
 				}
@@ -503,7 +492,19 @@ func (scope *Scope) Exec() *Scope {
 	defer scope.Trace(NowFunc()) // 日志
 
 	if !scope.HasError() {
-		result, err := scope.DB().Exec(scope.Sql, scope.SqlVars...)
+		var (
+			result sql.Result
+			err    error
+		)
+
+		// 优先走ExecContext，这样scope.Context()里的取消/超时才能传到driver，
+		// 退化到普通Exec是为了兼容没有实现sqlCommonContext的旧driver
+		if db, ok := scope.DB().(sqlCommonContext); ok {
+			result, err = db.ExecContext(scope.Context(), scope.Sql, scope.SqlVars...)
+		} else {
+			result, err = scope.DB().Exec(scope.Sql, scope.SqlVars...)
+		}
+
 		if scope.Err(err) == nil { // 如果没有错误，获取并设置影响的行数
 			if count, err := result.RowsAffected(); err == nil {
 				scope.db.RowsAffected = count
@@ -550,7 +551,18 @@ func (scope *Scope) Trace(t time.Time) {
 }
 
 // 开始事务    一个完整的支持事务需要实现sqlCommon, sqlTx, sqlDb三个接口
+// 底层连接实现了sqlDbContext的话优先用BeginTx带上context，这样事务也能被取消/超时中断
 func (scope *Scope) Begin() *Scope {
+	if db, ok := scope.DB().(sqlDbContext); ok {
+		if tx, err := db.BeginTx(scope.Context(), nil); err == nil {
+			scope.db.db = interface{}(tx).(sqlCommon)
+			// 事务标记
+			scope.InstanceSet("gorm:started_transaction", true)
+		} else {
+			scope.Err(err)
+		}
+		return scope
+	}
 	if db, ok := scope.DB().(sqlDb); ok {
 		if tx, err := db.Begin(); err == nil {
 			//
@@ -0,0 +1,175 @@
+package gorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// whereSql 根据Search里积累的条件构造WHERE子句
+func (scope *Scope) whereSql() (sql string) {
+	var andConditions []string
+
+	for _, clause := range scope.Search.whereConditions {
+		if query, ok := clause["query"]; ok {
+			args, _ := clause["args"].([]interface{})
+			if str := scope.buildCondition(query, args); str != "" {
+				andConditions = append(andConditions, str)
+			}
+		} else if str := scope.buildWhereFromMap(clause); str != "" {
+			andConditions = append(andConditions, str)
+		}
+	}
+
+	if len(andConditions) > 0 {
+		sql = "WHERE " + strings.Join(andConditions, " AND ")
+	}
+	return
+}
+
+// joinsSql 根据Search里的Joins条件构造JOIN子句
+func (scope *Scope) joinsSql() string {
+	if len(scope.Search.joinConditions) == 0 {
+		return ""
+	}
+	return " " + strings.Join(scope.Search.joinConditions, " ") + " "
+}
+
+// groupSql 构造GROUP BY子句
+func (scope *Scope) groupSql() string {
+	if scope.Search.groupConditions == "" {
+		return ""
+	}
+	return " GROUP BY " + scope.Search.groupConditions
+}
+
+// havingSql 构造HAVING子句
+func (scope *Scope) havingSql() string {
+	if len(scope.Search.havingConditions) == 0 {
+		return ""
+	}
+
+	var conditions []string
+	for _, clause := range scope.Search.havingConditions {
+		if str := scope.buildWhereFromMap(clause); str != "" {
+			conditions = append(conditions, str)
+		}
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " HAVING " + strings.Join(conditions, " AND ")
+}
+
+// orderSql 构造ORDER BY子句
+func (scope *Scope) orderSql() string {
+	if len(scope.Search.orders) == 0 {
+		return ""
+	}
+	return " ORDER BY " + strings.Join(scope.Search.orders, ",")
+}
+
+// limitSql 构造LIMIT子句
+func (scope *Scope) limitSql() string {
+	if scope.Search.limit == nil {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %v", scope.Search.limit)
+}
+
+// offsetSql 构造OFFSET子句
+func (scope *Scope) offsetSql() string {
+	if scope.Search.offset == nil {
+		return ""
+	}
+	return fmt.Sprintf(" OFFSET %v", scope.Search.offset)
+}
+
+// buildCondition 把用户传入的query/args构造成SQL片段
+func (scope *Scope) buildCondition(query interface{}, args []interface{}) string {
+	switch value := query.(type) {
+	case string:
+		return scope.buildWhereFromString(value, args)
+	case map[string]interface{}:
+		return scope.buildWhereFromMap(value)
+	default:
+		return ""
+	}
+}
+
+// buildWhereFromString 处理原始SQL片段，将其中的占位符"?"跟args对应起来，
+// 如果某个arg是slice，展开成"(?,?,?)"形式，用于IN查询
+func (scope *Scope) buildWhereFromString(str string, values []interface{}) string {
+	offset := 0
+	for _, value := range values {
+		placeholder := "?"
+		reflectValue := reflect.Indirect(reflect.ValueOf(value))
+		if reflectValue.Kind() == reflect.Slice && reflectValue.Type() != reflect.TypeOf([]byte{}) {
+			var placeholders []string
+			for i := 0; i < reflectValue.Len(); i++ {
+				placeholders = append(placeholders, "?")
+				scope.SqlVars = append(scope.SqlVars, reflectValue.Index(i).Interface())
+			}
+			placeholder = "(" + strings.Join(placeholders, ",") + ")"
+		} else {
+			scope.SqlVars = append(scope.SqlVars, value)
+		}
+		// 从上一个参数的占位符之后继续找，展开出来的"?"不会被当成下一个参数的占位符
+		if idx := strings.Index(str[offset:], "?"); idx != -1 {
+			str = str[:offset+idx] + placeholder + str[offset+idx+1:]
+			offset += idx + len(placeholder)
+		}
+	}
+	return str
+}
+
+// buildWhereFromMap 将map[string]interface{}形式的条件构造成SQL片段，
+// key可以带上beego风格的lookup后缀（如age__gte），未知后缀按原key做等值比较
+func (scope *Scope) buildWhereFromMap(conditions map[string]interface{}) string {
+	var sqls []string
+	for key, value := range conditions {
+		column, operator := parseLookupKey(key)
+		sqls = append(sqls, scope.buildLookupCondition(column, operator, value))
+	}
+	return strings.Join(sqls, " AND ")
+}
+
+// callCallbacks 依次执行回调函数，直到被SkipLeft或者出错才终止
+func (scope *Scope) callCallbacks(fns []func(scope *Scope)) *Scope {
+	for _, fn := range fns {
+		fn(scope)
+		if scope.skipLeft || scope.HasError() {
+			break
+		}
+	}
+	return scope
+}
+
+// scan 把一行结果扫描到fields对应的字段上，没有匹配的列会被忽略
+func (scope *Scope) scan(rows *sql.Rows, columns []string, fields map[string]*Field) {
+	values := make([]interface{}, len(columns))
+	for index, column := range columns {
+		if field, ok := fields[column]; ok {
+			values[index] = field.Field.Addr().Interface()
+		} else {
+			var ignored interface{}
+			values[index] = &ignored
+		}
+	}
+	scope.Err(rows.Scan(values...))
+}
+
+// getColumnAsArray 取出Scope.Value（单个struct或slice）中某个字段的所有值
+func (scope *Scope) getColumnAsArray(fieldName string) (columns []interface{}) {
+	values := scope.IndirectValue()
+	switch values.Kind() {
+	case reflect.Slice:
+		for i := 0; i < values.Len(); i++ {
+			columns = append(columns, reflect.Indirect(reflect.Indirect(values.Index(i)).FieldByName(fieldName)).Interface())
+		}
+	case reflect.Struct:
+		columns = append(columns, values.FieldByName(fieldName).Interface())
+	}
+	return
+}
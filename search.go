@@ -0,0 +1,65 @@
+package gorm
+
+// search 保存一次查找的所有条件，随着链式调用不断累积
+type search struct {
+	db               *DB
+	whereConditions  []map[string]interface{}
+	orConditions     []map[string]interface{}
+	notConditions    []map[string]interface{}
+	selects          map[string]interface{}
+	omits            []string
+	initAttrs        []interface{}
+	assignAttrs      []interface{}
+	joinConditions   []string
+	groupConditions  string
+	havingConditions []map[string]interface{}
+	orders           []string
+	preload          []searchPreload
+	offset           interface{}
+	limit            interface{}
+	unscoped         bool
+	Raw              bool
+	TableName        string
+}
+
+// searchPreload 记录一次Preload调用的关联名与附加条件
+type searchPreload struct {
+	schema     string
+	conditions []interface{}
+}
+
+// clone 克隆一份search，保证链式调用之间互不影响
+func (s *search) clone() *search {
+	clone := *s
+
+	clone.whereConditions = append([]map[string]interface{}{}, s.whereConditions...)
+	clone.orConditions = append([]map[string]interface{}{}, s.orConditions...)
+	clone.notConditions = append([]map[string]interface{}{}, s.notConditions...)
+	clone.havingConditions = append([]map[string]interface{}{}, s.havingConditions...)
+	clone.joinConditions = append([]string{}, s.joinConditions...)
+	clone.initAttrs = append([]interface{}{}, s.initAttrs...)
+	clone.assignAttrs = append([]interface{}{}, s.assignAttrs...)
+	clone.orders = append([]string{}, s.orders...)
+	clone.omits = append([]string{}, s.omits...)
+	clone.preload = append([]searchPreload{}, s.preload...)
+
+	return &clone
+}
+
+// Where 添加查找条件
+func (s *search) where(query interface{}, values ...interface{}) *search {
+	if len(values) == 0 {
+		if condition, ok := query.(map[string]interface{}); ok {
+			s.whereConditions = append(s.whereConditions, condition)
+			return s
+		}
+	}
+	s.whereConditions = append(s.whereConditions, map[string]interface{}{"query": query, "args": values})
+	return s
+}
+
+// preloadAssociation 记录一次Preload调用
+func (s *search) preloadAssociation(schema string, values ...interface{}) *search {
+	s.preload = append(s.preload, searchPreload{schema: schema, conditions: values})
+	return s
+}
@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NowFunc 返回当前时间，测试时可以替换成固定时间
+var NowFunc = func() time.Time {
+	return time.Now()
+}
+
+var smallLetters = regexp.MustCompile("([a-z0-9])([A-Z])")
+var bigLetters = regexp.MustCompile("([A-Z]+)([A-Z][a-z])")
+
+// ToSnake 驼峰命名转蛇形命名，例如 HTTPServer -> http_server
+func ToSnake(name string) string {
+	name = bigLetters.ReplaceAllString(name, "${1}_${2}")
+	name = smallLetters.ReplaceAllString(name, "${1}_${2}")
+	name = strings.Replace(name, "-", "_", -1)
+	return strings.ToLower(name)
+}
+
+// SnakeToUpperCamel 蛇形命名转大驼峰命名，例如 http_server -> HttpServer
+func SnakeToUpperCamel(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// parseTagSetting 解析gorm tag，形如 "size:255;not null"
+func parseTagSetting(str string) map[string]string {
+	setting := map[string]string{}
+	for _, value := range strings.Split(str, ";") {
+		if value == "" {
+			continue
+		}
+		v := strings.Split(value, ":")
+		k := strings.TrimSpace(strings.ToUpper(v[0]))
+		if len(v) >= 2 {
+			setting[k] = strings.Join(v[1:], ":")
+		} else {
+			setting[k] = k
+		}
+	}
+	return setting
+}
+
+// isBlank 判断reflect.Value是否是其类型的零值
+func isBlank(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// FieldValueByName 根据字段名获取结构体字段的值
+func FieldValueByName(name string, value interface{}) (interface{}, error) {
+	data := reflect.Indirect(reflect.ValueOf(value))
+	if data.Kind() != reflect.Struct {
+		return nil, errors.New("value must be a struct")
+	}
+
+	field := data.FieldByName(name)
+	if !field.IsValid() {
+		return nil, fmt.Errorf("could not find field %v", name)
+	}
+	return field.Interface(), nil
+}
+
+// fileWithLineNum 获取调用者所在的文件名及行号，用于日志和错误信息
+func fileWithLineNum() string {
+	for i := 2; i < 15; i++ {
+		if _, file, line, ok := runtime.Caller(i); ok && !strings.HasSuffix(file, "_test.go") {
+			return file + ":" + strconv.Itoa(line)
+		}
+	}
+	return ""
+}
+
+// typeName 获取Scope指向值的类型名，panic信息等场景使用
+func (scope *Scope) typeName() string {
+	typ := scope.IndirectValue().Type()
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Name()
+}